@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// csvFormat is the canonical "wide" interchange format used to hand
+// translations off to translators: one row per key (or, for plural/select
+// messages, one row per variant), one column per language, with a Variant
+// column identifying which CLDR plural category or select case the row
+// holds. Variant is blank for plain, non-varying messages. Quoting and
+// escaping of commas, quotes and newlines is handled by encoding/csv.
+type csvFormat struct{}
+
+func (csvFormat) Encode(mt messageTable, langs []string, outs ...io.WriteCloser) error {
+	if len(outs) != 1 {
+		return fmt.Errorf("csv: expected exactly 1 writer, got %d", len(outs))
+	}
+	out := outs[0]
+	w := csv.NewWriter(out)
+	if err := w.Write(append([]string{"Key", "Variant"}, langs...)); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(mt) {
+		byLang := mt[key]
+		cats, simple := variantsFor(byLang)
+		for _, cat := range cats {
+			row := make([]string, 0, len(langs)+2)
+			row = append(row, key)
+			if simple {
+				row = append(row, "")
+			} else {
+				row = append(row, cat)
+			}
+			for _, lang := range langs {
+				msg := byLang[lang]
+				if msg == nil {
+					row = append(row, "")
+					continue
+				}
+				row = append(row, msg.Variants[cat])
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// variantsFor returns the ordered set of variant categories used by key
+// across all its languages, and whether the key is a plain message (in
+// which case that set is just {VariantOther}).
+func variantsFor(byLang map[string]*Message) (cats []string, simple bool) {
+	simple = true
+	set := make(map[string]bool)
+	for _, msg := range byLang {
+		if msg == nil {
+			continue
+		}
+		if !msg.Simple() {
+			simple = false
+		}
+		for cat := range msg.Variants {
+			set[cat] = true
+		}
+	}
+	if simple {
+		return []string{VariantOther}, true
+	}
+	return orderedCategories(set), false
+}
+
+func (csvFormat) Decode(mt messageTable, ins []io.Reader, _ []string) ([]string, error) {
+	if len(ins) != 1 {
+		return nil, fmt.Errorf("csv: expected exactly 1 reader, got %d", len(ins))
+	}
+	r := csv.NewReader(ins[0])
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(header) < 2 || header[0] != "Key" || header[1] != "Variant" {
+		return nil, fmt.Errorf("csv: expected header starting with Key,Variant")
+	}
+	langs := header[2:]
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("csv: row has %d field(s), expected at least 2 (Key,Variant): %q", len(rec), rec)
+		}
+		key, variant := rec[0], rec[1]
+		if variant == "" {
+			variant = VariantOther
+		}
+		values := rec[2:]
+		if mt[key] == nil {
+			mt[key] = make(map[string]*Message)
+		}
+		for i, lang := range langs {
+			if i >= len(values) {
+				continue
+			}
+			// A cell may itself be a hand-written or extracted ICU
+			// plural/select block; explode it into its own variants so it
+			// round-trips onto one row per form on the next generation.
+			if arg, kind, vs, ok := parseICUVariants(values[i]); ok && variant == VariantOther {
+				mt[key][lang] = &Message{Arg: arg, Kind: kind, Variants: vs}
+				continue
+			}
+			msg := mt[key][lang]
+			if msg == nil {
+				msg = &Message{Variants: make(map[string]string)}
+				mt[key][lang] = msg
+			}
+			msg.Variants[variant] = values[i]
+		}
+	}
+	for _, byLang := range mt {
+		for _, msg := range byLang {
+			if msg != nil && len(msg.Variants) > 1 && msg.Kind == "" {
+				msg.Kind = inferVariantKind(msg.Variants)
+			}
+		}
+	}
+	return langs, nil
+}
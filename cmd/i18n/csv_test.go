@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCsvRoundTripQuoting(t *testing.T) {
+	mt := messageTable{
+		"greeting": {"en": newSimpleMessage(`hi, "friend"`)},
+		"note":     {"en": newSimpleMessage("line one\nline two")},
+	}
+	var buf bytes.Buffer
+	if err := (csvFormat{}).Encode(mt, []string{"en"}, nopWriteCloser{&buf}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got := make(messageTable)
+	langs, err := (csvFormat{}).Decode(got, []io.Reader{strings.NewReader(buf.String())}, nil)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(langs) != 1 || langs[0] != "en" {
+		t.Fatalf("unexpected langs: %v", langs)
+	}
+	for key, wantMsg := range mt {
+		gotMsg := got[key]["en"]
+		if gotMsg == nil {
+			t.Fatalf("missing key %q", key)
+		}
+		if gotMsg.Other() != wantMsg["en"].Other() {
+			t.Fatalf("key %q: got %q, want %q", key, gotMsg.Other(), wantMsg["en"].Other())
+		}
+	}
+}
+
+func TestCsvRoundTripPluralAndSelect(t *testing.T) {
+	mt := messageTable{
+		"items": {"en": {Kind: "plural", Variants: map[string]string{
+			"one": "# item", "other": "# items",
+		}}},
+		"welcome": {"en": {Kind: "select", Variants: map[string]string{
+			"male": "He is here", "female": "She is here", "other": "They are here",
+		}}},
+	}
+	var buf bytes.Buffer
+	if err := (csvFormat{}).Encode(mt, []string{"en"}, nopWriteCloser{&buf}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got := make(messageTable)
+	if _, err := (csvFormat{}).Decode(got, []io.Reader{strings.NewReader(buf.String())}, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	items := got["items"]["en"]
+	if items.Kind != "plural" || len(items.Variants) != 2 {
+		t.Fatalf("items: got %+v", items)
+	}
+	welcome := got["welcome"]["en"]
+	if welcome.Kind != "select" || len(welcome.Variants) != 3 {
+		t.Fatalf("welcome: got %+v, want Kind select with 3 variants", welcome)
+	}
+	for cat, text := range map[string]string{"male": "He is here", "female": "She is here", "other": "They are here"} {
+		if welcome.Variants[cat] != text {
+			t.Fatalf("welcome variant %q: got %q, want %q", cat, welcome.Variants[cat], text)
+		}
+	}
+}
+
+func TestCsvDecodeRejectsShortRow(t *testing.T) {
+	in := "Key,Variant,en\ngreeting,,hi\nbadrow\n"
+	mt := make(messageTable)
+	if _, err := (csvFormat{}).Decode(mt, []io.Reader{strings.NewReader(in)}, nil); err == nil {
+		t.Fatal("expected an error for a row with too few fields")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCsvEncodeIsDeterministic(t *testing.T) {
+	mt := messageTable{
+		"zebra": {"en": newSimpleMessage("z")},
+		"apple": {"en": newSimpleMessage("a")},
+		"mango": {"en": newSimpleMessage("m")},
+	}
+	encode := func() string {
+		var buf bytes.Buffer
+		if err := (csvFormat{}).Encode(mt, []string{"en"}, nopWriteCloser{&buf}); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		return buf.String()
+	}
+	first := encode()
+	for i := 0; i < 5; i++ {
+		if got := encode(); got != first {
+			t.Fatalf("encode is not deterministic across runs:\n%s\nvs\n%s", first, got)
+		}
+	}
+	want := "Key,Variant,en\napple,,a\nmango,,m\nzebra,,z\n"
+	if first != want {
+		t.Fatalf("got %q, want %q", first, want)
+	}
+}
+
+func TestGenerateCsvIndependentOfWalkOrder(t *testing.T) {
+	fsys := newMemFS()
+	// memFS walks in path order, so the subdirectories make the walk visit
+	// fr_FR, en_US, de_DE: the reverse of the expected column order.
+	fsys.writeFile("/res/a/uiMessages_fr_FR.properties", "greeting=salut\n")
+	fsys.writeFile("/res/b/uiMessages_en_US.properties", "greeting=hi\n")
+	fsys.writeFile("/res/c/uiMessages_de_DE.properties", "greeting=hallo\n")
+
+	if err := generateCsv(fsys, "/res", "/out"); err != nil {
+		t.Fatalf("generateCsv: %v", err)
+	}
+	rc, err := fsys.Open("/out/uiMessages.csv")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer rc.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(rc); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	want := "Key,Variant,de_DE,en_US,fr_FR\ngreeting,,hallo,hi,salut\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
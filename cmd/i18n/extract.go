@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// extractUsage describes the "extract" subcommand, shown by "i18n extract -h".
+func extractUsage() {
+	fmt.Fprint(os.Stderr, `Usage: i18n extract -dir ./path/to/package -bundle ./uiMessages.csv
+
+walks a Go package for calls to a translation function (i18n.T("key", ...) by
+default; override with -func pkg.Func) and merges any keys it finds into an
+existing csv or properties bundle, preserving translations already there and
+adding newly discovered keys untranslated. Pass -prune to also report keys in
+the bundle that are no longer referenced anywhere in the package.
+`)
+	os.Exit(2)
+}
+
+// runExtract implements the "extract" subcommand.
+func runExtract(fsys FS, args []string) error {
+	fset := flag.NewFlagSet("extract", flag.ExitOnError)
+	fset.Usage = extractUsage
+	dir := fset.String("dir", ".", "directory of Go source to scan")
+	bundle := fset.String("bundle", "", "path to the csv or properties bundle to update")
+	funcName := fset.String("func", "i18n.T", "qualified function whose first string-literal argument is a message key, e.g. i18n.T or printer.Sprintf")
+	prune := fset.Bool("prune", false, "report keys present in the bundle but no longer referenced in code")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if *bundle == "" {
+		return fmt.Errorf("extract: -bundle is required")
+	}
+	keys, err := extractKeys(*dir, *funcName)
+	if err != nil {
+		return err
+	}
+	added, unreferenced, err := mergeBundle(fsys, *bundle, keys)
+	if err != nil {
+		return err
+	}
+	for _, key := range added {
+		log.Printf("added untranslated key %q", key)
+	}
+	if *prune {
+		for _, key := range unreferenced {
+			log.Printf("%q is in the bundle but no longer referenced in %s", key, *dir)
+		}
+	}
+	return nil
+}
+
+// extractKeys parses every Go file in dir and returns the sorted, deduped
+// set of string literal keys passed as the first argument to funcName (a
+// "pkg.Func" selector, e.g. "i18n.T" or "printer.Sprintf"). It matches on
+// the literal selector text rather than resolved types, so it has no
+// dependency on go/types or the package's import graph.
+func extractKeys(dir, funcName string) (alphabetic, error) {
+	parts := strings.SplitN(funcName, ".", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("extract: -func must be of the form pkg.Func, got %q", funcName)
+	}
+	pkgIdent, fnIdent := parts[0], parts[1]
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var keys alphabetic
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				x, ok := sel.X.(*ast.Ident)
+				if !ok || x.Name != pkgIdent || sel.Sel.Name != fnIdent {
+					return true
+				}
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				key, err := strconv.Unquote(lit.Value)
+				if err != nil || seen[key] {
+					return true
+				}
+				seen[key] = true
+				keys = append(keys, key)
+				return true
+			})
+		}
+	}
+	sort.Sort(keys)
+	return keys, nil
+}
+
+// loadBundle opens path (a csv or properties resource file) and decodes it,
+// tolerating a missing file so extract can populate a bundle from scratch.
+func loadBundle(fsys FS, path string) (messageTable, []string, codec, error) {
+	mt := make(messageTable)
+	if strings.HasSuffix(path, ".csv") {
+		f, err := fsys.Open(path)
+		if os.IsNotExist(err) {
+			return mt, nil, codecs["csv"], nil
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		defer f.Close()
+		langs, err := codecs["csv"].Decode(mt, []io.Reader{f}, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return mt, langs, codecs["csv"], nil
+	}
+	name, err := resourceFormat(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rc := resourceCodecs[name]
+	lang, ok := langFromFileName(path, rc.Ext())
+	if !ok {
+		lang = "default"
+	}
+	f, err := fsys.Open(path)
+	if os.IsNotExist(err) {
+		return mt, []string{lang}, rc, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer f.Close()
+	if _, err := rc.Decode(mt, []io.Reader{f}, []string{lang}); err != nil {
+		return nil, nil, nil, err
+	}
+	return mt, []string{lang}, rc, nil
+}
+
+// mergeBundle adds any of keys missing from the bundle at path as
+// untranslated entries, leaving existing translations untouched, and
+// reports which bundle keys aren't in keys anymore.
+func mergeBundle(fsys FS, path string, keys []string) (added, unreferenced []string, err error) {
+	mt, langs, c, err := loadBundle(fsys, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	referenced := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		referenced[key] = true
+		if mt[key] != nil {
+			continue
+		}
+		mt[key] = make(map[string]*Message)
+		for _, lang := range langs {
+			mt[key][lang] = newSimpleMessage("")
+		}
+		added = append(added, key)
+	}
+	var stale alphabetic
+	for key := range mt {
+		if !referenced[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Sort(stale)
+	out, err := fsys.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Encode(mt, langs, out); err != nil {
+		return nil, nil, err
+	}
+	return added, stale, nil
+}
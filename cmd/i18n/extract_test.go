@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractKeys(t *testing.T) {
+	dir := t.TempDir()
+	src := `package app
+
+import "example.com/i18n"
+
+func greet() {
+	_ = i18n.T("greeting.hello")
+	_ = i18n.T("greeting.bye")
+	_ = i18n.T("greeting.hello")
+	_ = otherPkg.T("not.a.match")
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	keys, err := extractKeys(dir, "i18n.T")
+	if err != nil {
+		t.Fatalf("extractKeys: %v", err)
+	}
+	want := []string{"greeting.bye", "greeting.hello"}
+	if len(keys) != len(want) {
+		t.Fatalf("got %v, want %v", []string(keys), want)
+	}
+	for i, k := range want {
+		if keys[i] != k {
+			t.Fatalf("got %v, want %v", []string(keys), want)
+		}
+	}
+}
+
+func TestMergeBundleAddsAndReportsStale(t *testing.T) {
+	fsys := newMemFS()
+	fsys.writeFile("/bundle.csv", "Key,Variant,en\nexisting,,translated\nstale,,old\n")
+
+	added, stale, err := mergeBundle(fsys, "/bundle.csv", []string{"existing", "new.key"})
+	if err != nil {
+		t.Fatalf("mergeBundle: %v", err)
+	}
+	if len(added) != 1 || added[0] != "new.key" {
+		t.Fatalf("unexpected added: %v", added)
+	}
+	if len(stale) != 1 || stale[0] != "stale" {
+		t.Fatalf("unexpected stale: %v", stale)
+	}
+
+	f, err := fsys.Open("/bundle.csv")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	out, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	text := string(out)
+	if !strings.Contains(text, "existing,,translated") {
+		t.Fatalf("expected existing translation to survive, got:\n%s", text)
+	}
+	if !strings.Contains(text, "new.key,,\n") && !strings.Contains(text, "new.key,,") {
+		t.Fatalf("expected new.key to be added untranslated, got:\n%s", text)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var formatFlag = flag.String("format", "", "resource format to read/write: properties, gotext (defaults to the extension of the input resource files, or to properties when writing resource files from a csv)")
+
+// Encoder serializes a messageTable to one or more destinations. csv is a
+// "wide" interchange format with every language in a single file, so its
+// Encode expects exactly one writer. properties and gotext are "spread"
+// resource formats with one file per language, so their Encode expects one
+// writer per language in langs, in the same order.
+type Encoder interface {
+	Encode(mt messageTable, langs []string, outs ...io.WriteCloser) error
+}
+
+// Decoder parses a messageTable back out of one or more sources, the
+// inverse of Encoder. It merges into mt and returns the languages it found
+// (or, for per-language formats that can't self-describe, simply echoes
+// back the langs it was given).
+type Decoder interface {
+	Decode(mt messageTable, ins []io.Reader, langs []string) ([]string, error)
+}
+
+type codec interface {
+	Encoder
+	Decoder
+}
+
+// resourceCodec is a codec for a per-language resource format, i.e.
+// everything except csv.
+type resourceCodec interface {
+	codec
+	// Ext is the canonical file suffix for this format, e.g. ".properties".
+	Ext() string
+}
+
+// selfDescribingResourceCodec is implemented by resource formats that carry
+// their language inside the file itself instead of in the file name (e.g.
+// gotext's "language" field), so file discovery can't rely on
+// langFromFileName for them.
+type selfDescribingResourceCodec interface {
+	resourceCodec
+	// PeekLanguage reads r and returns the language it describes, without
+	// decoding its messages.
+	PeekLanguage(r io.Reader) (string, error)
+}
+
+var codecs = map[string]codec{
+	"properties": propertiesFormat{},
+	"csv":        csvFormat{},
+	"gotext":     gotextFormat{},
+}
+
+var resourceCodecs = map[string]resourceCodec{
+	"properties": propertiesFormat{},
+	"gotext":     gotextFormat{},
+}
+
+// detectResourceFormat identifies the resource format of path from its file
+// name, e.g. "uiMessages_en.properties" -> "properties".
+func detectResourceFormat(path string) (string, bool) {
+	switch {
+	case strings.HasSuffix(path, ".gotext.json"):
+		return "gotext", true
+	case strings.HasSuffix(path, ".properties"):
+		return "properties", true
+	default:
+		return "", false
+	}
+}
+
+// resourceFormat resolves which resourceCodec to use for a resource file
+// named name: the explicit -format flag if set, otherwise whatever name's
+// extension implies.
+func resourceFormat(name string) (string, error) {
+	if *formatFlag != "" {
+		return *formatFlag, nil
+	}
+	f, ok := detectResourceFormat(name)
+	if !ok {
+		return "", fmt.Errorf("cannot infer resource format from file name %q, pass -format", name)
+	}
+	return f, nil
+}
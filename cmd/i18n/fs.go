@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FS is the filesystem abstraction threaded through the tool's entry point.
+// It covers exactly what i18n needs: opening resource files for reading,
+// creating generated files, stat-ing the input path, and walking a
+// directory tree. osFS is the real implementation used by main; tests use
+// an in-memory one instead of touching disk.
+type FS interface {
+	Open(name string) (io.ReadCloser, error)
+	Create(name string) (io.WriteCloser, error)
+	Stat(name string) (os.FileInfo, error)
+	Walk(root string, fn filepath.WalkFunc) error
+}
+
+// osFS is the FS backed by the real operating system filesystem.
+type osFS struct{}
+
+func (osFS) Open(name string) (io.ReadCloser, error) { return os.Open(name) }
+
+func (osFS) Create(name string) (io.WriteCloser, error) {
+	return os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+}
+
+func (osFS) Stat(name string) (os.FileInfo, error) { return os.Stat(name) }
+
+func (osFS) Walk(root string, fn filepath.WalkFunc) error { return filepath.Walk(root, fn) }
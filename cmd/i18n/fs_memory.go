@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// memFS is an in-memory FS, used by tests to round-trip whole directory
+// trees of resource files without touching disk.
+type memFS struct {
+	files map[string]*memFile
+}
+
+type memFile struct {
+	data []byte
+	dir  bool
+}
+
+// newMemFS returns an empty in-memory FS. Seed it with writeFile before use.
+func newMemFS() *memFS {
+	return &memFS{files: make(map[string]*memFile)}
+}
+
+// writeFile seeds path with contents, creating any parent directories
+// implied by it.
+func (m *memFS) writeFile(path, contents string) {
+	path = filepath.Clean(path)
+	for dir := filepath.Dir(path); dir != "." && dir != "/"; dir = filepath.Dir(dir) {
+		if _, ok := m.files[dir]; !ok {
+			m.files[dir] = &memFile{dir: true}
+		}
+	}
+	m.files[path] = &memFile{data: []byte(contents)}
+}
+
+func (m *memFS) Open(name string) (io.ReadCloser, error) {
+	name = filepath.Clean(name)
+	f, ok := m.files[name]
+	if !ok || f.dir {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+func (m *memFS) Create(name string) (io.WriteCloser, error) {
+	return &memWriter{fs: m, name: filepath.Clean(name)}, nil
+}
+
+// memWriter buffers writes until Close, mirroring the all-at-once nature of
+// os.OpenFile followed by a single Write in the codecs.
+type memWriter struct {
+	fs   *memFS
+	name string
+	buf  bytes.Buffer
+}
+
+func (w *memWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *memWriter) Close() error {
+	w.fs.writeFile(w.name, w.buf.String())
+	return nil
+}
+
+func (m *memFS) Stat(name string) (os.FileInfo, error) {
+	name = filepath.Clean(name)
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return memFileInfo{name: filepath.Base(name), dir: f.dir, size: int64(len(f.data))}, nil
+}
+
+func (m *memFS) Walk(root string, fn filepath.WalkFunc) error {
+	root = filepath.Clean(root)
+	rootInfo, err := m.Stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	if err := fn(root, rootInfo, nil); err != nil {
+		return err
+	}
+	var names []string
+	for name := range m.files {
+		if strings.HasPrefix(name, root+string(filepath.Separator)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := m.files[name]
+		if err := fn(name, memFileInfo{name: filepath.Base(name), dir: f.dir, size: int64(len(f.data))}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memFileInfo is the os.FileInfo implementation for memFS entries.
+type memFileInfo struct {
+	name string
+	dir  bool
+	size int64
+}
+
+func (fi memFileInfo) Name() string { return fi.name }
+func (fi memFileInfo) Size() int64  { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir
+	}
+	return 0644
+}
+func (fi memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi memFileInfo) IsDir() bool        { return fi.dir }
+func (fi memFileInfo) Sys() interface{}   { return nil }
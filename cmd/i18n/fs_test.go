@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestMemFS(t *testing.T) {
+	fsys := newMemFS()
+	fsys.writeFile("/res/uiMessages_en.properties", "greeting=hi\n")
+
+	fi, err := fsys.Stat("/res/uiMessages_en.properties")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.IsDir() {
+		t.Fatalf("expected a regular file")
+	}
+
+	rc, err := fsys.Open("/res/uiMessages_en.properties")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != "greeting=hi\n" {
+		t.Fatalf("got %q", got)
+	}
+
+	wc, err := fsys.Create("/out/uiMessages_en.properties")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := wc.Write([]byte("greeting=hi\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := fsys.Stat("/out/uiMessages_en.properties"); err != nil {
+		t.Fatalf("expected written file to exist: %v", err)
+	}
+
+	var seen []string
+	err = fsys.Walk("/res", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			seen = append(seen, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	if len(seen) != 1 || seen[0] != "/res/uiMessages_en.properties" {
+		t.Fatalf("unexpected walk result: %v", seen)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCsvAndBackRoundTrip(t *testing.T) {
+	fsys := newMemFS()
+	fsys.writeFile("/res/uiMessages_en_US.properties", "greeting=hi\nfarewell=bye\n")
+	fsys.writeFile("/res/uiMessages_fr_FR.properties", "greeting=salut\nfarewell=au revoir\n")
+
+	if err := generateCsv(fsys, "/res", "/out"); err != nil {
+		t.Fatalf("generateCsv: %v", err)
+	}
+
+	rc, err := fsys.Open("/out/uiMessages.csv")
+	if err != nil {
+		t.Fatalf("Open generated csv: %v", err)
+	}
+	csvBytes, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	csvText := string(csvBytes)
+	for _, want := range []string{"Key,Variant,en_US,fr_FR", "greeting,,hi,salut", "farewell,,bye,au revoir"} {
+		if !strings.Contains(csvText, want) {
+			t.Fatalf("expected csv to contain %q, got:\n%s", want, csvText)
+		}
+	}
+
+	if err := generateResourceFiles(fsys, "/out/uiMessages.csv", "/out2"); err != nil {
+		t.Fatalf("generateResourceFiles: %v", err)
+	}
+
+	for _, tc := range []struct {
+		file string
+		want []string
+	}{
+		{"/out2/uiMessages_en_US.properties", []string{"greeting=hi", "farewell=bye"}},
+		{"/out2/uiMessages_fr_FR.properties", []string{"greeting=salut", "farewell=au revoir"}},
+	} {
+		f, err := fsys.Open(tc.file)
+		if err != nil {
+			t.Fatalf("Open %s: %v", tc.file, err)
+		}
+		got, err := io.ReadAll(f)
+		if err != nil {
+			t.Fatalf("ReadAll %s: %v", tc.file, err)
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(string(got), want) {
+				t.Fatalf("expected %s to contain %q, got:\n%s", tc.file, want, got)
+			}
+		}
+	}
+}
+
+// TestGenerateCsvFromGotextLocaleDirs exercises the directory-per-locale
+// layout gotext update actually produces (e.g. "locales/en/messages.gotext.json"),
+// where the language lives in the file's "language" field, not its name.
+func TestGenerateCsvFromGotextLocaleDirs(t *testing.T) {
+	fsys := newMemFS()
+	fsys.writeFile("/locales/en/messages.gotext.json", `{
+		"language": "en",
+		"messages": [{"id": "greeting", "message": "hi", "translation": "hi"}]
+	}`)
+	fsys.writeFile("/locales/fr/messages.gotext.json", `{
+		"language": "fr",
+		"messages": [{"id": "greeting", "message": "hi", "translation": "salut"}]
+	}`)
+
+	if err := generateCsv(fsys, "/locales", "/out"); err != nil {
+		t.Fatalf("generateCsv: %v", err)
+	}
+	rc, err := fsys.Open("/out/uiMessages.csv")
+	if err != nil {
+		t.Fatalf("Open generated csv: %v", err)
+	}
+	csvBytes, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	csvText := string(csvBytes)
+	for _, want := range []string{"Key,Variant,en,fr", "greeting,,hi,salut"} {
+		if !strings.Contains(csvText, want) {
+			t.Fatalf("expected csv to contain %q, got:\n%s", want, csvText)
+		}
+	}
+}
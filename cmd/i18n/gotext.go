@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// gotextFormat reads and writes messages.gotext.json files, the format
+// produced and consumed by golang.org/x/text/message/pipeline (e.g. via
+// `go generate ./... && gotext update`). Like properties, it is spread
+// across one file per language. It doesn't model per-variant rows the way
+// csv does, so plural/select messages are carried as a single reassembled
+// ICU block.
+type gotextFormat struct{}
+
+func (gotextFormat) Ext() string { return ".gotext.json" }
+
+// PeekLanguage reads a messages.gotext.json file and returns its "language"
+// field; the messages are skipped rather than decoded. gotext files are
+// laid out one per locale directory (e.g. "locales/en/messages.gotext.json")
+// with no language in the file name, so generateCsv uses this instead of
+// langFromFileName to discover their language.
+func (gotextFormat) PeekLanguage(r io.Reader) (string, error) {
+	var gf struct {
+		Language string `json:"language"`
+	}
+	if err := json.NewDecoder(r).Decode(&gf); err != nil {
+		return "", err
+	}
+	return gf.Language, nil
+}
+
+type gotextMessage struct {
+	ID          string `json:"id"`
+	Message     string `json:"message"`
+	Translation string `json:"translation"`
+}
+
+type gotextFile struct {
+	Language string          `json:"language"`
+	Messages []gotextMessage `json:"messages"`
+}
+
+func (gotextFormat) Encode(mt messageTable, langs []string, outs ...io.WriteCloser) error {
+	if len(outs) != len(langs) {
+		return fmt.Errorf("gotext: expected %d writers for %d languages, got %d", len(langs), len(langs), len(outs))
+	}
+	for idx, out := range outs {
+		lang := langs[idx]
+		gf := gotextFile{Language: lang}
+		for _, key := range sortedKeys(mt) {
+			byLang := mt[key]
+			msg := byLang[lang]
+			if msg == nil {
+				continue
+			}
+			// message carries the source-language string for translator
+			// context; we treat the first language column as the source.
+			gf.Messages = append(gf.Messages, gotextMessage{
+				ID:          key,
+				Message:     renderMessage(byLang[langs[0]]),
+				Translation: renderMessage(msg),
+			})
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(gf); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gotextFormat) Decode(mt messageTable, ins []io.Reader, langs []string) ([]string, error) {
+	resolved := make([]string, 0, len(ins))
+	for idx, in := range ins {
+		var gf gotextFile
+		if err := json.NewDecoder(in).Decode(&gf); err != nil {
+			return nil, err
+		}
+		lang := gf.Language
+		if lang == "" && idx < len(langs) {
+			lang = langs[idx]
+		}
+		resolved = append(resolved, lang)
+		for _, m := range gf.Messages {
+			if mt[m.ID] == nil {
+				mt[m.ID] = make(map[string]*Message)
+			}
+			if arg, kind, variants, ok := parseICUVariants(m.Translation); ok {
+				mt[m.ID][lang] = &Message{Arg: arg, Kind: kind, Variants: variants}
+			} else {
+				mt[m.ID][lang] = newSimpleMessage(m.Translation)
+			}
+		}
+	}
+	return resolved, nil
+}
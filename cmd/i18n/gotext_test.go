@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGotextRoundTripSimple(t *testing.T) {
+	mt := messageTable{"greeting": {"en": newSimpleMessage("hi")}}
+	var buf bytes.Buffer
+	if err := (gotextFormat{}).Encode(mt, []string{"en"}, nopWriteCloser{&buf}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got := make(messageTable)
+	langs, err := (gotextFormat{}).Decode(got, []io.Reader{strings.NewReader(buf.String())}, nil)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(langs) != 1 || langs[0] != "en" {
+		t.Fatalf("unexpected langs: %v", langs)
+	}
+	if got["greeting"]["en"].Other() != "hi" {
+		t.Fatalf("got %q, want %q", got["greeting"]["en"].Other(), "hi")
+	}
+}
+
+func TestGotextRoundTripPluralAndSelect(t *testing.T) {
+	mt := messageTable{
+		"items": {"en": {Arg: "count", Kind: "plural", Variants: map[string]string{
+			"one": "# item", "other": "# items",
+		}}},
+		"welcome": {"en": {Arg: "gender", Kind: "select", Variants: map[string]string{
+			"male": "He is here", "female": "She is here", "other": "They are here",
+		}}},
+	}
+	var buf bytes.Buffer
+	if err := (gotextFormat{}).Encode(mt, []string{"en"}, nopWriteCloser{&buf}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got := make(messageTable)
+	if _, err := (gotextFormat{}).Decode(got, []io.Reader{strings.NewReader(buf.String())}, []string{"en"}); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	items := got["items"]["en"]
+	if items.Kind != "plural" || items.Arg != "count" || len(items.Variants) != 2 {
+		t.Fatalf("items: got %+v", items)
+	}
+	welcome := got["welcome"]["en"]
+	if welcome.Kind != "select" || welcome.Arg != "gender" || len(welcome.Variants) != 3 {
+		t.Fatalf("welcome: got %+v", welcome)
+	}
+	for cat, text := range map[string]string{"male": "He is here", "female": "She is here", "other": "They are here"} {
+		if welcome.Variants[cat] != text {
+			t.Fatalf("welcome variant %q: got %q, want %q", cat, welcome.Variants[cat], text)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// wellKnownPluralCategories are the CLDR plural categories, in the order
+// they should appear whenever a plural message is rendered back out.
+var wellKnownPluralCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+// parseICUVariants does a minimal parse of a single ICU MessageFormat
+// plural or select block, e.g. "{count, plural, one {# item} other {# items}}"
+// or "{gender, select, male {he} female {she} other {they}}". It understands
+// only a flat list of "category {text}" cases with balanced braces; it does
+// not support nested arguments or formats other than plural/select. That's
+// enough to explode a hand-written or extracted ICU message into one row
+// per variant, and to reassemble it afterwards.
+func parseICUVariants(s string) (arg, kind string, variants map[string]string, ok bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return "", "", nil, false
+	}
+	body := s[1 : len(s)-1]
+	argEnd := strings.Index(body, ",")
+	if argEnd < 0 {
+		return "", "", nil, false
+	}
+	arg = strings.TrimSpace(body[:argEnd])
+	rest := strings.TrimSpace(body[argEnd+1:])
+	kindEnd := strings.Index(rest, ",")
+	if kindEnd < 0 {
+		return "", "", nil, false
+	}
+	kind = strings.TrimSpace(rest[:kindEnd])
+	if kind != "plural" && kind != "select" {
+		return "", "", nil, false
+	}
+	cases := strings.TrimSpace(rest[kindEnd+1:])
+	variants = make(map[string]string)
+	for len(cases) > 0 {
+		open := strings.Index(cases, "{")
+		if open < 0 {
+			break
+		}
+		category := strings.TrimSpace(cases[:open])
+		depth := 1
+		i := open + 1
+		for ; i < len(cases) && depth > 0; i++ {
+			switch cases[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		if depth != 0 || category == "" {
+			return "", "", nil, false
+		}
+		variants[category] = cases[open+1 : i-1]
+		cases = strings.TrimSpace(cases[i:])
+	}
+	if len(variants) == 0 {
+		return "", "", nil, false
+	}
+	return arg, kind, variants, true
+}
+
+// formatICUVariants is the inverse of parseICUVariants: it reassembles a
+// plural/select block from its variants.
+func formatICUVariants(arg, kind string, variants map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	sb.WriteString(arg)
+	sb.WriteString(", ")
+	sb.WriteString(kind)
+	sb.WriteString(", ")
+	for _, cat := range orderedVariantKeys(variants) {
+		sb.WriteString(cat)
+		sb.WriteString(" {")
+		sb.WriteString(variants[cat])
+		sb.WriteString("} ")
+	}
+	return strings.TrimSpace(sb.String()) + "}"
+}
+
+// orderedVariantKeys returns variants' keys with well-known CLDR plural
+// categories first (in CLDR order), followed by any remaining select cases
+// sorted alphabetically.
+func orderedVariantKeys(variants map[string]string) []string {
+	set := make(map[string]bool, len(variants))
+	for k := range variants {
+		set[k] = true
+	}
+	return orderedCategories(set)
+}
+
+// orderedCategories orders a set of plural/select category names the same
+// way orderedVariantKeys does.
+func orderedCategories(cats map[string]bool) []string {
+	var ordered []string
+	known := make(map[string]bool, len(wellKnownPluralCategories))
+	for _, wk := range wellKnownPluralCategories {
+		known[wk] = true
+		if cats[wk] {
+			ordered = append(ordered, wk)
+		}
+	}
+	var rest alphabetic
+	for c := range cats {
+		if !known[c] {
+			rest = append(rest, c)
+		}
+	}
+	sort.Sort(rest)
+	return append(ordered, rest...)
+}
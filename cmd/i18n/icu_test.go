@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestParseICUVariantsPlural(t *testing.T) {
+	arg, kind, variants, ok := parseICUVariants("{count, plural, one {# item} other {# items}}")
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if arg != "count" || kind != "plural" {
+		t.Fatalf("got arg %q kind %q, want count/plural", arg, kind)
+	}
+	want := map[string]string{"one": "# item", "other": "# items"}
+	for cat, text := range want {
+		if variants[cat] != text {
+			t.Fatalf("variant %q: got %q, want %q", cat, variants[cat], text)
+		}
+	}
+}
+
+func TestParseICUVariantsSelect(t *testing.T) {
+	arg, kind, variants, ok := parseICUVariants("{gender, select, male {he} female {she} other {they}}")
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if arg != "gender" || kind != "select" {
+		t.Fatalf("got arg %q kind %q, want gender/select", arg, kind)
+	}
+	want := map[string]string{"male": "he", "female": "she", "other": "they"}
+	for cat, text := range want {
+		if variants[cat] != text {
+			t.Fatalf("variant %q: got %q, want %q", cat, variants[cat], text)
+		}
+	}
+}
+
+func TestParseICUVariantsRejectsNonICU(t *testing.T) {
+	for _, s := range []string{"", "plain text", "{count, number}", "{count, plural}"} {
+		if _, _, _, ok := parseICUVariants(s); ok {
+			t.Fatalf("parseICUVariants(%q): expected not ok", s)
+		}
+	}
+}
+
+func TestFormatICUVariantsRoundTrip(t *testing.T) {
+	want := "{gender, select, other {they} female {she} male {he}}"
+	got := formatICUVariants("gender", "select", map[string]string{
+		"male": "he", "female": "she", "other": "they",
+	})
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	arg, kind, variants, ok := parseICUVariants(got)
+	if !ok || arg != "gender" || kind != "select" || len(variants) != 3 {
+		t.Fatalf("round trip failed: arg=%q kind=%q variants=%v ok=%v", arg, kind, variants, ok)
+	}
+}
+
+func TestOrderedCategoriesPluralFirstThenAlphabetical(t *testing.T) {
+	got := orderedCategories(map[string]bool{"female": true, "other": true, "male": true, "one": true})
+	want := []string{"one", "other", "female", "male"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
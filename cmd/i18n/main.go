@@ -1,16 +1,16 @@
 // i18n is a command line utility that assists in maintaining code bases that need to serve
 // internationalization and localization purposes. It allows for the quick conversion between typical formats like .csv
-// to application resource files (e.g .properties) and vice versa for message files found commonly in i18n applications.
+// to application resource files (e.g .properties or messages.gotext.json) and vice versa for message files found commonly in i18n applications.
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -18,9 +18,12 @@ var input = flag.String("i", "", "input path: either a directory or a file")
 
 func usage() {
 	fmt.Fprint(os.Stderr, `Usage: i18n -i ./path/to/my/resources -o ./my/generated/test.csv OR i18n -i ./path/to/test.csv -o /some/dir/path
+       i18n extract -dir ./path/to/package -bundle ./uiMessages.csv
 
-provided an input path and a output path i18n determines whether or not to generate a .csv 
-file or a set of .properties files.
+provided an input path and a output path i18n determines whether or not to generate a .csv
+file or a set of resource files (.properties or messages.gotext.json, selected with -format).
+The "extract" subcommand instead pulls translatable strings out of Go source; run
+"i18n extract -h" for its flags.
 `)
 	os.Exit(2)
 }
@@ -28,18 +31,24 @@ file or a set of .properties files.
 func main() {
 	log.SetFlags(0)
 	log.SetPrefix("i18n: ")
+	if len(os.Args) > 1 && os.Args[1] == "extract" {
+		if err := runExtract(osFS{}, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	flag.Usage = usage
 	flag.Parse()
 	if flag.NFlag() == 0 {
 		usage()
 	}
-	if err := i18n(); err != nil {
+	if err := i18n(osFS{}); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func i18n() error {
-	fi, err := os.Stat(*input)
+func i18n(fsys FS) error {
+	fi, err := fsys.Stat(*input)
 	if err != nil {
 		return err
 	}
@@ -47,167 +56,136 @@ func i18n() error {
 	if err != nil {
 		return err
 	}
-	inputIsDir := fi.IsDir()
-	if inputIsDir {
-		return generateCsv(*input, cwd)
+	if fi.IsDir() {
+		return generateCsv(fsys, *input, cwd)
 	}
-	return generateResourceFiles(*input, cwd)
+	return generateResourceFiles(fsys, *input, cwd)
 }
 
-type messageTable map[string][]string
-
-func (mt messageTable) properties(outs ...io.WriteCloser) error {
-	var sb strings.Builder
-	for idx, out := range outs {
-		for key, ts := range mt {
-			if _, err := sb.WriteString(fmt.Sprintf("%s=%s\n", key, ts[idx])); err != nil {
-				return err
-			}
-		}
-		if _, err := out.Write([]byte(sb.String())); err != nil {
+// generateCsv walks inputDir for resource files in a single format
+// (.properties or .gotext.json, detected from the file names or forced via
+// -format), and writes their combined translations as a csv file.
+func generateCsv(fsys FS, inputDir, outputFile string) error {
+	var fmtName string
+	var files, languages []string
+	err := fsys.Walk(inputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
 			return err
 		}
-		out.Close()
-		sb.Reset()
-	}
-	return nil
-}
-
-func (mt messageTable) csv(out io.Writer) error {
-	var sb strings.Builder
-	for key, translations := range mt {
-		ts := strings.Join(translations, ",")
-		if _, err := sb.WriteString(fmt.Sprintf("%s,%s\n", key, ts)); err != nil {
+		if info.IsDir() {
+			return nil
+		}
+		if _, ok := detectResourceFormat(path); !ok {
+			return nil
+		}
+		name, err := resourceFormat(path)
+		if err != nil {
 			return err
 		}
-	}
-	if _, err := out.Write([]byte(sb.String())); err != nil {
-		return err
-	}
-	return nil
-}
-
-func generateCsv(inputDir, outputFile string) error {
-	files := make([]string, 0)
-	languages := make([]string, 0)
-	err := filepath.Walk(inputDir, func(path string, info os.FileInfo, err error) error {
-		if strings.HasSuffix(path, ".properties") {
-			parts := strings.Split(path, "_")
-			if len(parts) != 3 {
-				return fmt.Errorf("expected 3 parts from split with \"_\" found in the file name: %s, found %d", path, len(parts))
+		if fmtName == "" {
+			fmtName = name
+		} else if fmtName != name {
+			return fmt.Errorf("mixed resource formats in %s: found both %q and %q", inputDir, fmtName, name)
+		}
+		lang, ok := langFromFileName(path, resourceCodecs[name].Ext())
+		if !ok {
+			sd, ok := resourceCodecs[name].(selfDescribingResourceCodec)
+			if !ok {
+				return nil
+			}
+			f, err := fsys.Open(path)
+			if err != nil {
+				return err
+			}
+			lang, err = sd.PeekLanguage(f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			if lang == "" {
+				return nil
 			}
-			languages = append(languages, strings.ReplaceAll(strings.Join(parts[1:], "_"), ".properties", ""))
-			files = append(files, path)
 		}
+		languages = append(languages, lang)
+		files = append(files, path)
 		return nil
 	})
-	if len(files) == 0 {
-		return fmt.Errorf("no .properties files found in the input directory %s", *input)
-	}
 	if err != nil {
 		return err
 	}
-	path := filepath.Join(outputFile, "uiMessages.csv")
-	if err := writeCsv(languages, files, path); err != nil {
-		return err
+	if len(files) == 0 {
+		return fmt.Errorf("no resource files found in the input directory %s", inputDir)
 	}
-	return nil
-}
-
-func generateResourceFiles(inputFile, outputDir string) error {
+	// Sort by language, not by walk order, so the generated csv's column
+	// order doesn't depend on the filesystem's directory-listing order.
+	sort.Sort(byLanguage{languages: languages, files: files})
+	rc := resourceCodecs[fmtName]
 	mt := make(messageTable)
-	langs, err := readCsv(inputFile, mt)
-	if err != nil {
-		return err
-	}
-	var outs []io.WriteCloser
-	for _, lang := range langs {
-		fileName := filepath.Join(outputDir, fmt.Sprintf("uiMessages_%s.properties", lang))
-		fp, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE, 0755)
+	var ins []io.Reader
+	for _, f := range files {
+		fp, err := fsys.Open(f)
 		if err != nil {
 			return err
 		}
-		outs = append(outs, fp)
+		defer fp.Close()
+		ins = append(ins, fp)
 	}
-	if err := mt.properties(outs...); err != nil {
+	langs, err := rc.Decode(mt, ins, languages)
+	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func readResource(file string, mt messageTable) error {
-	fp, err := os.Open(file)
-	defer fp.Close()
+	path := filepath.Join(outputFile, "uiMessages.csv")
+	out, err := fsys.Create(path)
 	if err != nil {
 		return err
 	}
-	scan := bufio.NewScanner(fp)
-	lineNum := 0
-	for scan.Scan() {
-		lineNum++
-		l := scan.Text()
-		// if line is empty or there is a comment in the properties file
-		if l == "" || strings.HasPrefix(l, "#") {
-			continue
-		}
-		parts := strings.Split(l, "=")
-		if len(parts) != 2 {
-			return fmt.Errorf("expected 2 parts found %d on line %d", len(parts), lineNum)
-		}
-		k := strings.TrimSpace(parts[0])
-		v := strings.TrimSpace(parts[1])
-		if _, ok := mt[k]; !ok {
-			mt[k] = make([]string, 0)
-		}
-		mt[k] = append(mt[k], v)
-	}
-	return nil
+	return codecs["csv"].Encode(mt, langs, out)
 }
 
-func readCsv(file string, mt messageTable) ([]string, error) {
-	fp, err := os.Open(file)
+// generateResourceFiles reads a csv file and writes it back out as one
+// resource file per language, in the format chosen by -format (properties
+// by default).
+func generateResourceFiles(fsys FS, inputFile, outputDir string) error {
+	fp, err := fsys.Open(inputFile)
 	if err != nil {
-		return nil, err
-	}
-	scan := bufio.NewScanner(fp)
-	scan.Scan()
-	header := scan.Text()
-	langs := strings.Split(header, ",")[1:]
-	for scan.Scan() {
-		l := scan.Text()
-		if l == "" {
-			continue
-		}
-		parts := strings.Split(l, ",")
-		if _, ok := mt[parts[0]]; !ok {
-			mt[parts[0]] = make([]string, len(parts)-1)
-		}
-		mt[parts[0]] = parts[1:]
-	}
-	return langs, nil
-}
-
-func writeCsv(langs, resourceFiles []string, outputFile string) error {
-	mt := make(messageTable)
-	for _, file := range resourceFiles {
-		if err := readResource(file, mt); err != nil {
-			return err
-		}
+		return err
 	}
-	fp, err := os.OpenFile(outputFile, os.O_RDWR|os.O_CREATE, 0755)
 	defer fp.Close()
+	mt := make(messageTable)
+	langs, err := codecs["csv"].Decode(mt, []io.Reader{fp}, nil)
 	if err != nil {
 		return err
 	}
-	var sb strings.Builder
-	ls := strings.Join(langs, ",")
-	// write header
-	sb.WriteString(fmt.Sprintf("%s,%s\n", "Key", ls))
-	if _, err := fp.WriteString(sb.String()); err != nil {
-		return err
+	name := *formatFlag
+	if name == "" {
+		name = "properties"
 	}
-	if err := mt.csv(fp); err != nil {
-		return err
+	rc, ok := resourceCodecs[name]
+	if !ok {
+		return fmt.Errorf("unknown resource format %q", name)
 	}
-	return nil
+	var outs []io.WriteCloser
+	for _, lang := range langs {
+		fileName := filepath.Join(outputDir, fmt.Sprintf("uiMessages_%s%s", lang, rc.Ext()))
+		f, err := fsys.Create(fileName)
+		if err != nil {
+			return err
+		}
+		outs = append(outs, f)
+	}
+	return rc.Encode(mt, langs, outs...)
+}
+
+// langFromFileName extracts the language code from a resource file named
+// "<prefix>_<lang>ext", e.g. "uiMessages_en_US.properties" -> "en_US". It
+// reports false when the base name holds fewer than two "_", so a name like
+// "uiMessages_en.properties" is not recognized.
+func langFromFileName(path, ext string) (string, bool) {
+	base := filepath.Base(path)
+	parts := strings.Split(base, "_")
+	if len(parts) < 3 {
+		return "", false
+	}
+	lang := strings.TrimSuffix(strings.Join(parts[1:], "_"), ext)
+	return lang, true
 }
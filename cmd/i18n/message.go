@@ -0,0 +1,72 @@
+package main
+
+import "sort"
+
+// VariantOther is the CLDR plural category, and our default select case,
+// used for every message that doesn't vary by plural or select form.
+const VariantOther = "other"
+
+// Message is a single translatable entry for one key in one language. A
+// plain message carries only the Other variant; a plural or select message
+// carries one translation per CLDR plural category or select case, keyed
+// in Variants.
+type Message struct {
+	// Arg is the ICU argument name the message varies on, e.g. "count" for
+	// a plural or "gender" for a select. Empty for plain messages.
+	Arg string
+	// Kind is "plural" or "select"; empty for plain messages.
+	Kind     string
+	Variants map[string]string
+}
+
+// newSimpleMessage wraps a plain translation with no plural/select forms.
+func newSimpleMessage(value string) *Message {
+	return &Message{Variants: map[string]string{VariantOther: value}}
+}
+
+// Simple reports whether m is a plain, non-varying message.
+func (m *Message) Simple() bool {
+	return m.Kind == "" && len(m.Variants) <= 1
+}
+
+// Other returns m's Other variant, the translation used when m is plain or
+// as the plural/select fallback form.
+func (m *Message) Other() string {
+	return m.Variants[VariantOther]
+}
+
+// renderMessage returns msg as a single string: the Other variant for a plain
+// message, or a reassembled ICU plural/select block otherwise. It returns
+// "" for a nil Message.
+func renderMessage(msg *Message) string {
+	if msg == nil {
+		return ""
+	}
+	if msg.Simple() {
+		return msg.Other()
+	}
+	arg, kind := msg.Arg, msg.Kind
+	if arg == "" {
+		arg = "count"
+	}
+	if kind == "" {
+		kind = "plural"
+	}
+	return formatICUVariants(arg, kind, msg.Variants)
+}
+
+// messageTable maps a message key to its translations for each language;
+// each translation may itself carry plural/select variants.
+type messageTable map[string]map[string]*Message
+
+// sortedKeys returns mt's keys in deterministic, lexicographic order, so
+// that generated files are byte-identical across runs and produce stable
+// diffs.
+func sortedKeys(mt messageTable) alphabetic {
+	keys := make(alphabetic, 0, len(mt))
+	for k := range mt {
+		keys = append(keys, k)
+	}
+	sort.Sort(keys)
+	return keys
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// propertiesFormat reads and writes Java-style .properties resource files,
+// one file per language, following the java.util.Properties conventions:
+// "#" and "!" start comment lines, "=" or ":" separate key from value, a
+// trailing unescaped "\" continues a logical line onto the next, and
+// "\n", "\t", "\r", "\f", "\\" and "\uXXXX" are recognized escapes.
+//
+// Plural/select messages are written using the conventional "key.one=",
+// "key.other=" suffixes; a key with no recognized suffix is a plain
+// message.
+type propertiesFormat struct{}
+
+func (propertiesFormat) Ext() string { return ".properties" }
+
+func (propertiesFormat) Encode(mt messageTable, langs []string, outs ...io.WriteCloser) error {
+	if len(outs) != len(langs) {
+		return fmt.Errorf("properties: expected %d writers for %d languages, got %d", len(langs), len(langs), len(outs))
+	}
+	keys := sortedKeys(mt)
+	var sb strings.Builder
+	for idx, out := range outs {
+		lang := langs[idx]
+		for _, key := range keys {
+			msg := mt[key][lang]
+			if msg == nil {
+				continue
+			}
+			if msg.Simple() {
+				sb.WriteString(fmt.Sprintf("%s=%s\n", key, escapeProperties(msg.Other())))
+				continue
+			}
+			for _, cat := range orderedVariantKeys(msg.Variants) {
+				sb.WriteString(fmt.Sprintf("%s.%s=%s\n", key, cat, escapeProperties(msg.Variants[cat])))
+			}
+		}
+		if _, err := out.Write([]byte(sb.String())); err != nil {
+			return err
+		}
+		out.Close()
+		sb.Reset()
+	}
+	return nil
+}
+
+func (propertiesFormat) Decode(mt messageTable, ins []io.Reader, langs []string) ([]string, error) {
+	for i, in := range ins {
+		var lang string
+		if i < len(langs) {
+			lang = langs[i]
+		}
+		lines, err := readLogicalLines(bufio.NewScanner(in))
+		if err != nil {
+			return nil, err
+		}
+		type entry struct{ key, value string }
+		var entries []entry
+		for lineNum, l := range lines {
+			l = strings.TrimSpace(l)
+			if l == "" || strings.HasPrefix(l, "#") || strings.HasPrefix(l, "!") {
+				continue
+			}
+			rawKey, rawValue, ok := splitKeyValue(l)
+			if !ok {
+				return nil, fmt.Errorf("expected a key/value pair on line %d", lineNum+1)
+			}
+			k, err := unescapeProperties(strings.TrimSpace(rawKey))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+			}
+			v, err := unescapeProperties(strings.TrimSpace(rawValue))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum+1, err)
+			}
+			entries = append(entries, entry{k, v})
+		}
+		// A dotted key is only a plural/select variant if its base also has
+		// an "other" sibling among this file's keys: select cases have no
+		// fixed vocabulary, but ICU always requires an "other" fallback, so
+		// that's what tells "items.one"/"items.other" apart from an
+		// ordinary dotted key like "app.title".
+		hasOther := make(map[string]bool)
+		for _, e := range entries {
+			if base, cat, ok := splitVariantKey(e.key); ok && cat == VariantOther {
+				hasOther[base] = true
+			}
+		}
+		variantBases := make(map[string]bool)
+		for _, e := range entries {
+			base, cat, isVariant := splitVariantKey(e.key)
+			if !isVariant || !hasOther[base] {
+				base, cat = e.key, VariantOther
+			} else {
+				variantBases[base] = true
+			}
+			if mt[base] == nil {
+				mt[base] = make(map[string]*Message)
+			}
+			msg := mt[base][lang]
+			if msg == nil {
+				msg = &Message{Variants: make(map[string]string)}
+				mt[base][lang] = msg
+			}
+			msg.Variants[cat] = e.value
+		}
+		for base := range variantBases {
+			msg := mt[base][lang]
+			msg.Kind = inferVariantKind(msg.Variants)
+		}
+	}
+	return langs, nil
+}
+
+// pluralCategories recognizes the CLDR plural category suffixes used by the
+// "key.one=", "key.other=" convention.
+var pluralCategories = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+// inferVariantKind reports whether a message's variant categories describe
+// a plural or a select: "plural" if every category is a CLDR plural
+// category, "select" as soon as one isn't.
+func inferVariantKind(variants map[string]string) string {
+	for cat := range variants {
+		if !pluralCategories[cat] {
+			return "select"
+		}
+	}
+	return "plural"
+}
+
+// splitVariantKey splits a properties key like "items.one" into its base
+// key and suffix category on the last dot. It doesn't validate the suffix
+// against the known plural categories: select cases have arbitrary names,
+// so the caller decides whether a given suffix is really a variant (see the
+// "other" sibling check in Decode).
+func splitVariantKey(k string) (base, category string, ok bool) {
+	idx := strings.LastIndex(k, ".")
+	if idx < 0 {
+		return "", "", false
+	}
+	return k[:idx], k[idx+1:], true
+}
+
+// readLogicalLines joins java.util.Properties-style continuation lines
+// (a trailing unescaped "\") into single logical lines.
+func readLogicalLines(scan *bufio.Scanner) ([]string, error) {
+	var lines []string
+	var cur strings.Builder
+	continuing := false
+	for scan.Scan() {
+		l := scan.Text()
+		if continuing {
+			cur.WriteString(strings.TrimLeft(l, " \t"))
+		} else {
+			cur.Reset()
+			cur.WriteString(l)
+		}
+		if s := cur.String(); hasTrailingUnescapedBackslash(s) {
+			cur.Reset()
+			cur.WriteString(s[:len(s)-1])
+			continuing = true
+			continue
+		}
+		lines = append(lines, cur.String())
+		continuing = false
+	}
+	return lines, scan.Err()
+}
+
+// hasTrailingUnescapedBackslash reports whether s ends in a "\" that isn't
+// itself escaped, i.e. an odd number of trailing backslashes.
+func hasTrailingUnescapedBackslash(s string) bool {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitKeyValue splits a logical properties line into its raw (still
+// escaped) key and value on the first unescaped "=" or ":".
+func splitKeyValue(line string) (key, value string, ok bool) {
+	esc := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if esc {
+			esc = false
+			continue
+		}
+		switch c {
+		case '\\':
+			esc = true
+		case '=', ':':
+			return line[:i], line[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// unescapeProperties decodes the standard java.util.Properties escapes:
+// \n \t \r \f \\ and \uXXXX unicode escapes. Any other "\x" is taken
+// literally as "x", which also covers escaped "=", ":" and "#".
+func unescapeProperties(s string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			sb.WriteByte('\\')
+			break
+		}
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("invalid \\u escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid \\u escape %q: %w", s[i+1:i+5], err)
+			}
+			sb.WriteRune(rune(r))
+			i += 4
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), nil
+}
+
+// escapeProperties is the inverse of unescapeProperties for the control
+// characters that must never appear literally in a .properties value.
+func escapeProperties(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\\':
+			sb.WriteString(`\\`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// nopWriteCloser adapts a bytes.Buffer to io.WriteCloser for tests, since
+// codecs always close their output writers.
+type nopWriteCloser struct{ *bytes.Buffer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestUnescapeProperties(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"newline", `a\nb`, "a\nb"},
+		{"tab", `a\tb`, "a\tb"},
+		{"backslash", `a\\b`, `a\b`},
+		{"unicode", `caf\u00e9`, "café"},
+		{"escaped separator", `a\:b\=c`, "a:b=c"},
+		{"plain", "hello world", "hello world"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := unescapeProperties(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnescapePropertiesInvalidUnicode(t *testing.T) {
+	if _, err := unescapeProperties(`\u00`); err == nil {
+		t.Fatal("expected an error for a truncated \\u escape")
+	}
+}
+
+func TestPropertiesDecodeEscapesAndContinuation(t *testing.T) {
+	in := "# a comment\n" +
+		"! also a comment\n" +
+		"greeting=hi\\nthere\n" +
+		"path:C\\:\\\\data\n" +
+		"long=this is a \\\n" +
+		"    continued value\n"
+	mt := make(messageTable)
+	langs, err := (propertiesFormat{}).Decode(mt, []io.Reader{strings.NewReader(in)}, []string{"en"})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(langs) != 1 || langs[0] != "en" {
+		t.Fatalf("unexpected langs: %v", langs)
+	}
+	want := map[string]string{
+		"greeting": "hi\nthere",
+		"path":     `C:\data`,
+		"long":     "this is a continued value",
+	}
+	for key, wantValue := range want {
+		msg := mt[key]["en"]
+		if msg == nil {
+			t.Fatalf("missing key %q", key)
+		}
+		if got := msg.Other(); got != wantValue {
+			t.Fatalf("key %q: got %q, want %q", key, got, wantValue)
+		}
+	}
+}
+
+func TestPropertiesDecodePluralVariants(t *testing.T) {
+	in := "items.one=# item\nitems.other=# items\n"
+	mt := make(messageTable)
+	if _, err := (propertiesFormat{}).Decode(mt, []io.Reader{strings.NewReader(in)}, []string{"en"}); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	msg := mt["items"]["en"]
+	if msg == nil {
+		t.Fatal("missing key \"items\"")
+	}
+	if msg.Kind != "plural" {
+		t.Fatalf("got Kind %q, want plural", msg.Kind)
+	}
+	if msg.Variants["one"] != "# item" || msg.Variants["other"] != "# items" {
+		t.Fatalf("got variants %v", msg.Variants)
+	}
+}
+
+func TestPropertiesDecodeSelectVariants(t *testing.T) {
+	in := "welcome.male=He is here\nwelcome.female=She is here\nwelcome.other=They are here\n"
+	mt := make(messageTable)
+	if _, err := (propertiesFormat{}).Decode(mt, []io.Reader{strings.NewReader(in)}, []string{"en"}); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(mt) != 1 {
+		t.Fatalf("got %d top-level keys, want 1 (%v)", len(mt), mt)
+	}
+	msg := mt["welcome"]["en"]
+	if msg == nil {
+		t.Fatal("missing key \"welcome\"")
+	}
+	if msg.Kind != "select" {
+		t.Fatalf("got Kind %q, want select", msg.Kind)
+	}
+	want := map[string]string{"male": "He is here", "female": "She is here", "other": "They are here"}
+	for cat, text := range want {
+		if msg.Variants[cat] != text {
+			t.Fatalf("variant %q: got %q, want %q", cat, msg.Variants[cat], text)
+		}
+	}
+}
+
+func TestPropertiesDecodeOrdinaryDottedKeyIsNotAVariant(t *testing.T) {
+	in := "app.title=My App\n"
+	mt := make(messageTable)
+	if _, err := (propertiesFormat{}).Decode(mt, []io.Reader{strings.NewReader(in)}, []string{"en"}); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	msg := mt["app.title"]["en"]
+	if msg == nil {
+		t.Fatal("missing key \"app.title\"")
+	}
+	if !msg.Simple() {
+		t.Fatalf("got %+v, want a simple message", msg)
+	}
+}
+
+func TestPropertiesEncodeEscapesNewlines(t *testing.T) {
+	mt := messageTable{"greeting": {"en": newSimpleMessage("hi\nthere")}}
+	var buf bytes.Buffer
+	if err := (propertiesFormat{}).Encode(mt, []string{"en"}, nopWriteCloser{&buf}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if got := buf.String(); got != `greeting=hi\nthere`+"\n" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+// alphabetic sorts strings lexicographically. It underlies the
+// deterministic key and variant ordering used across the message table
+// codecs.
+type alphabetic []string
+
+func (a alphabetic) Len() int           { return len(a) }
+func (a alphabetic) Less(i, j int) bool { return a[i] < a[j] }
+func (a alphabetic) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// byLanguage sorts a parallel (languages, files) pair by language code, so
+// resource files discovered in filesystem order can be placed in a
+// deterministic column order before encoding.
+type byLanguage struct {
+	languages []string
+	files     []string
+}
+
+func (b byLanguage) Len() int           { return len(b.languages) }
+func (b byLanguage) Less(i, j int) bool { return b.languages[i] < b.languages[j] }
+func (b byLanguage) Swap(i, j int) {
+	b.languages[i], b.languages[j] = b.languages[j], b.languages[i]
+	b.files[i], b.files[j] = b.files[j], b.files[i]
+}